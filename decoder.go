@@ -0,0 +1,106 @@
+package lamenv
+
+import (
+	"encoding"
+	"encoding/base64"
+	"reflect"
+	"time"
+)
+
+// Decoder is the interface a type can implement to take over how lamenv turns the raw
+// string value of an environment variable into its own representation.
+// It takes precedence over encoding.TextUnmarshaler and encoding.BinaryUnmarshaler
+// when a type happens to implement several of them.
+type Decoder interface {
+	Decode(value string) error
+}
+
+// RegisterDecoder associates a function able to build a value of type t from the raw
+// string of an environment variable. It is useful for types that cannot implement the
+// Decoder, encoding.TextUnmarshaler or encoding.BinaryUnmarshaler interface themselves,
+// typically because they are coming from another package.
+//
+// Calling RegisterDecoder again for a type already registered overrides the previous one,
+// which is also how the built-in decoders (time.Duration, []byte) can be replaced if the
+// default behavior doesn't fit. It can't be used to override the decoding of a type that
+// already implements Decoder, encoding.TextUnmarshaler or encoding.BinaryUnmarshaler (e.g.
+// time.Time, net.IP, url.URL), since decodeCustomValue always picks one of those first.
+func (l *Lamenv) RegisterDecoder(t reflect.Type, decode func(string) (interface{}, error)) *Lamenv {
+	l.decoders[t] = decode
+	return l
+}
+
+// registerBuiltinDecoders wires up the decoders for the stdlib types lamenv supports out of
+// the box and that do not already implement encoding.TextUnmarshaler or
+// encoding.BinaryUnmarshaler: decodeCustomValue checks those interfaces before falling back
+// to this registry, so registering a type that implements either here would only ever be
+// dead code. time.Time, net.IP and url.URL are notably absent for that reason: the first two
+// implement encoding.TextUnmarshaler and url.URL implements encoding.BinaryUnmarshaler
+// (on *url.URL), which decodes them just as a registered decoder would.
+func registerBuiltinDecoders(decoders map[reflect.Type]func(string) (interface{}, error)) {
+	decoders[reflect.TypeOf(time.Duration(0))] = func(value string) (interface{}, error) {
+		return time.ParseDuration(value)
+	}
+	decoders[reflect.TypeOf([]byte(nil))] = func(value string) (interface{}, error) {
+		return base64.StdEncoding.DecodeString(value)
+	}
+}
+
+// decodeCustom looks for a way to decode the environment variable matching parts that
+// bypasses the generic kind-based decoding: a lamenv.Decoder, an encoding.TextUnmarshaler,
+// an encoding.BinaryUnmarshaler, or a decoder registered through RegisterDecoder, in that
+// order of precedence. It returns true when one of them has taken over the decoding, in
+// which case opts' `min`, `max`, `oneof` and `pattern` constraints have already been
+// enforced against the raw value, exactly like decodeNative does for the generic kinds.
+func (l *Lamenv) decodeCustom(v reflect.Value, parts []string, opts fieldOptions) (bool, error) {
+	if !v.CanAddr() {
+		return false, nil
+	}
+	variable, input, ok := l.lookupEnv(parts)
+	if !ok {
+		return false, nil
+	}
+	handled, err := l.decodeCustomValue(v, input)
+	if handled {
+		// remove the variable to avoid to reuse it later
+		delete(l.env, variable)
+		if err == nil {
+			err = validateConstraints(v, opts, input)
+		}
+	}
+	return handled, err
+}
+
+// decodeCustomValue is the value based counterpart of decodeCustom: it decodes input
+// directly, without looking up an environment variable, which is what lets it also be
+// used to decode the tokens of a delimited slice or map.
+func (l *Lamenv) decodeCustomValue(v reflect.Value, input string) (bool, error) {
+	if !v.CanAddr() {
+		return false, nil
+	}
+	ptr := v.Addr().Interface()
+	decodeFn, hasDecodeFn := l.decoders[v.Type()]
+
+	dec, isDecoder := ptr.(Decoder)
+	textDec, isTextDec := ptr.(encoding.TextUnmarshaler)
+	binDec, isBinDec := ptr.(encoding.BinaryUnmarshaler)
+	if !isDecoder && !isTextDec && !isBinDec && !hasDecodeFn {
+		return false, nil
+	}
+
+	switch {
+	case isDecoder:
+		return true, dec.Decode(input)
+	case isTextDec:
+		return true, textDec.UnmarshalText([]byte(input))
+	case isBinDec:
+		return true, binDec.UnmarshalBinary([]byte(input))
+	default:
+		result, err := decodeFn(input)
+		if err != nil {
+			return true, err
+		}
+		v.Set(reflect.ValueOf(result))
+		return true, nil
+	}
+}
@@ -0,0 +1,109 @@
+package lamenv
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUnmarshal_BuiltinDecoders(t *testing.T) {
+	type Config struct {
+		Timeout time.Duration
+		When    time.Time
+		IP      net.IP
+		Site    url.URL
+		Blob    []byte
+	}
+
+	l := New().WithSource(MapSource{
+		"TIMEOUT": "30s",
+		"WHEN":    "2020-01-02T15:04:05Z",
+		"IP":      "192.168.1.1",
+		"SITE":    "https://example.com/path",
+		"BLOB":    "aGVsbG8=",
+	})
+	var c Config
+	if err := l.Unmarshal(&c, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want 30s", c.Timeout)
+	}
+	if !c.When.Equal(time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Errorf("When = %v", c.When)
+	}
+	if !c.IP.Equal(net.ParseIP("192.168.1.1")) {
+		t.Errorf("IP = %v", c.IP)
+	}
+	if c.Site.String() != "https://example.com/path" {
+		t.Errorf("Site = %v", c.Site.String())
+	}
+	if string(c.Blob) != "hello" {
+		t.Errorf("Blob = %q, want %q", c.Blob, "hello")
+	}
+}
+
+func TestRegisterBuiltinDecoders_NoDeadRegistrations(t *testing.T) {
+	// time.Time and net.IP both implement encoding.TextUnmarshaler, and url.URL implements
+	// encoding.BinaryUnmarshaler on its pointer receiver: decodeCustomValue checks those
+	// interfaces before falling back to the registered-decoder map, so registering any of
+	// them there would be dead code (see registerBuiltinDecoders' comment).
+	decoders := make(map[reflect.Type]func(string) (interface{}, error))
+	registerBuiltinDecoders(decoders)
+	for _, deadType := range []reflect.Type{
+		reflect.TypeOf(time.Time{}),
+		reflect.TypeOf(net.IP{}),
+		reflect.TypeOf(url.URL{}),
+	} {
+		if _, ok := decoders[deadType]; ok {
+			t.Errorf("registerBuiltinDecoders registers %s, which would never run", deadType)
+		}
+	}
+}
+
+func TestRegisterDecoder_CannotOverrideBinaryUnmarshaler(t *testing.T) {
+	// url.URL implements encoding.BinaryUnmarshaler, which decodeCustomValue always picks
+	// over a type registered through RegisterDecoder, so registering one for url.URL has no
+	// effect. This documents the limitation rather than asserting it's desirable.
+	called := false
+	l := New().WithSource(MapSource{"SITE": "https://example.com"})
+	l.RegisterDecoder(reflect.TypeOf(url.URL{}), func(value string) (interface{}, error) {
+		called = true
+		return url.URL{}, nil
+	})
+
+	type Config struct {
+		Site url.URL
+	}
+	var c Config
+	if err := l.Unmarshal(&c, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called {
+		t.Error("the registered decoder ran, expected encoding.BinaryUnmarshaler to take precedence")
+	}
+	if c.Site.String() != "https://example.com" {
+		t.Errorf("Site = %v, want the value decoded through UnmarshalBinary", c.Site.String())
+	}
+}
+
+func TestRegisterDecoder_OverridesBuiltin(t *testing.T) {
+	l := New()
+	l.RegisterDecoder(reflect.TypeOf(time.Duration(0)), func(value string) (interface{}, error) {
+		return time.Hour, nil
+	})
+	l.WithSource(MapSource{"TIMEOUT": "anything"})
+
+	type Config struct {
+		Timeout time.Duration
+	}
+	var c Config
+	if err := l.Unmarshal(&c, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Timeout != time.Hour {
+		t.Errorf("Timeout = %v, want the overridden 1h", c.Timeout)
+	}
+}
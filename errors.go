@@ -0,0 +1,32 @@
+package lamenv
+
+import "strings"
+
+// multiError aggregates several errors encountered while decoding a struct so that,
+// for example, every missing required environment variable can be reported at once
+// instead of failing on the first one found.
+type multiError []error
+
+func (m multiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// appendError appends err to errs when it is not nil and returns the resulting slice.
+func appendError(errs []error, err error) []error {
+	if err == nil {
+		return errs
+	}
+	return append(errs, err)
+}
+
+// joinErrors returns a single error aggregating errs, or nil if errs is empty.
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return multiError(errs)
+}
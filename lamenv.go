@@ -8,7 +8,6 @@ package lamenv
 
 import (
 	"fmt"
-	"os"
 	"reflect"
 	"strconv"
 	"strings"
@@ -32,6 +31,42 @@ var defaultTagSupported = []string{
 // "json", "yaml" and "mapstructure" name in the field tag.
 // If multiple tag name are defined, "json" is considered at first, then "yaml" and finally "mapstructure".
 //
+// A few extra tags can be used alongside those to fine-tune the behavior of a given field:
+//   - `default:"..."` provides a fallback value to use when no matching environment variable is found.
+//   - `required:"true"` makes Unmarshal return an error when no matching environment variable
+//     (and no default) is found. Every missing required field is reported together in a single
+//     aggregated error instead of failing on the first one found.
+//   - `split_words:"true"` converts a CamelCase field name into its SNAKE_CASE equivalent
+//     when no "json"/"yaml"/"mapstructure" tag is overriding the name (so an "APIKey" field
+//     is looked up as "..._API_KEY" instead of "..._APIKEY").
+//   - `lamenv:"separator=..."` lets a slice, or the entries of a map, be decoded from a single
+//     delimited environment variable (e.g. "rob,ken,robert") instead of the indexed/prefixed
+//     convention, when that variable exists. `lamenv:"keyval=..."`, used alongside it for a map,
+//     separates the key from the value inside each entry (e.g. "red:1,green:2"). Both can be set
+//     for every field of a Lamenv instead of per field through Lamenv.ListSeparator and Lamenv.MapKVSeparator.
+//     Setting both the delimited variable and at least one indexed/prefixed entry for the same
+//     field is an error, instead of silently picking one and ignoring the other.
+//   - `lamenv:"min=..."` and `lamenv:"max=..."` bound a numeric field's value, or a string
+//     field's length (in runes). `lamenv:"oneof=a|b|c"` restricts a field to an exhaustive set
+//     of values. `lamenv:"pattern=<regex>"` requires the raw value to match a regular
+//     expression. All four are enforced right after the field is decoded.
+//
+// Once the object is fully decoded, any addressable value implementing the Validator
+// interface (or a type for which a validator function has been registered through
+// Lamenv.RegisterValidator) is validated, at every depth of the tree: every error returned
+// this way is aggregated into Unmarshal's final error, the same way missing required fields are.
+//
+// By default, nested parts (prefix, struct fields, slice indexes, map keys) are joined with a
+// single "_", which is ambiguous with an underscore that is part of a field name. Set
+// Lamenv.WordSeparator (e.g. "__") to lift that ambiguity, and Lamenv.CaseSensitive to stop
+// uppercasing parts, see their doc comments for details.
+//
+// A field whose type implements the Decoder interface, encoding.TextUnmarshaler or
+// encoding.BinaryUnmarshaler (in that order of precedence), or whose type has been
+// registered through Lamenv.RegisterDecoder, is decoded using it instead of the
+// generic kind based decoding. time.Duration, time.Time (RFC3339), net.IP, url.URL
+// and []byte (base64) are supported out of the box this way.
+//
 // Note: When using a map, it's possible for the Unmarshal method to fail because it's finding multiple way to unmarshal
 // the same environment variable for different field in the struct (that could be at different depth).
 // It's usually because when using a map, the method has to guess which key to use to unmarshal the environment variable.
@@ -56,37 +91,107 @@ type Lamenv struct {
 	// TagSupports is a list of tag like "yaml", "json"
 	// that the code will look at it to know the name of the field
 	TagSupports []string
-	// env is the map that is representing the list of the environment variable visited
+	// sources is where the environment variables are read from, consulted in order
+	// (first match wins). It defaults to a single ProcessSource, and is replaced or
+	// extended through WithSource and LoadDotEnv.
+	sources []Source
+	// env is the map that is representing the list of the environment variable visited,
+	// snapshotted from sources.
 	// The key is the name of the variable.
 	// The value is not important, since once the variable would be used, then the key will be removed
 	// It will be useful when a map is involved in order to not parse every possible variable
 	// but only the one that are still not used.
 	env map[string]bool
+	// decoders holds the decoding functions registered through RegisterDecoder, keyed by the
+	// type they know how to build, and pre-populated with the built-in stdlib decoders.
+	decoders map[reflect.Type]func(string) (interface{}, error)
+	// encoders holds the encoding functions registered through RegisterEncoder, keyed by the
+	// type they know how to render, and pre-populated with the built-in stdlib encoders. It's
+	// the Marshal counterpart of decoders.
+	encoders map[reflect.Type]func(interface{}) (string, error)
+	// validators holds the validation functions registered through RegisterValidator, keyed
+	// by the type they know how to validate.
+	validators map[reflect.Type]func(interface{}) error
+	// ListSeparator, when set, allows a slice (or the entries of a map) to be decoded from a
+	// single environment variable holding delimited values (e.g. "rob,ken,robert") instead of
+	// the indexed/prefixed convention. It can be overridden per field with `lamenv:"separator=..."`.
+	ListSeparator string
+	// MapKVSeparator, when set alongside ListSeparator, separates the key from the value inside
+	// each entry of a delimited map (e.g. "red:1,green:2"). It can be overridden per field with
+	// `lamenv:"keyval=..."`.
+	MapKVSeparator string
+	// WordSeparator, when set, is used instead of "_" to join the parts (prefix, struct field
+	// names, slice indexes, map keys) that make up an environment variable name. The default,
+	// a single "_", is ambiguous: it can't tell a nesting boundary from an underscore that is
+	// part of a field name (e.g. a `yaml:"foo_bar"` tag), so "MY_PREFIX_FOO_BAR" could target
+	// either a top-level "FooBar" field or a nested "Foo.Bar" one. Setting WordSeparator to
+	// "__", the conventional double-underscore convention, lifts that ambiguity: a "MY_PREFIX"
+	// prefix and a "foo_bar" field name unambiguously build "MY_PREFIX__FOO_BAR". Kept empty
+	// by default for backward compatibility with the historical single-underscore behavior.
+	WordSeparator string
+	// CaseSensitive, when true, stops buildEnvVariable from uppercasing parts before joining
+	// them. It's useful together with a non-ProcessSource (a MapSource or a .env file, say)
+	// whose keys aren't all-caps. Environment variables looked up through ProcessSource are
+	// virtually always uppercase by convention, which is why this defaults to false.
+	CaseSensitive bool
 }
 
 // New is the method to use to initialize the struct Lamenv.
 // The struct can then be fine tuned using the appropriate exported method.
 func New() *Lamenv {
-	env := make(map[string]bool)
-	for _, e := range os.Environ() {
-		envSplit := strings.Split(e, "=")
-		if len(envSplit) != 2 {
-			continue
-		}
-		env[envSplit[0]] = true
-	}
-	return &Lamenv{
+	decoders := make(map[reflect.Type]func(string) (interface{}, error))
+	registerBuiltinDecoders(decoders)
+	encoders := make(map[reflect.Type]func(interface{}) (string, error))
+	registerBuiltinEncoders(encoders)
+	l := &Lamenv{
 		TagSupports: []string{
 			"yaml", "json", "mapstructure",
 		},
-		env: env,
+		sources:    []Source{ProcessSource{}},
+		decoders:   decoders,
+		encoders:   encoders,
+		validators: make(map[reflect.Type]func(interface{}) error),
+	}
+	l.resetEnv()
+	return l
+}
+
+// WithSource overrides the source(s) Lamenv reads environment variables from, which default
+// to the current process environment (a single ProcessSource). Passing several sources
+// layers them together in the order given, first match wins: e.g.
+// l.WithSource(lamenv.MapSource{"A": "1"}, lamenv.ProcessSource{}) lets an explicit override
+// take precedence over the process environment.
+func (l *Lamenv) WithSource(sources ...Source) *Lamenv {
+	l.sources = sources
+	l.resetEnv()
+	return l
+}
+
+// LoadDotEnv parses path as a .env file (see NewDotEnvSource for the expected format) and
+// adds it as a fallback source, consulted after whatever sources are already configured.
+func (l *Lamenv) LoadDotEnv(path string) error {
+	source, err := NewDotEnvSource(path)
+	if err != nil {
+		return err
+	}
+	l.sources = append(l.sources, source)
+	l.resetEnv()
+	return nil
+}
+
+// resetEnv (re-)snapshots the keys exposed by l.sources, to be consumed/deleted as they are
+// visited while decoding a map.
+func (l *Lamenv) resetEnv() {
+	l.env = make(map[string]bool)
+	for _, key := range MultiSource(l.sources).Keys() {
+		l.env[key] = true
 	}
 }
 
 // Unmarshal reads the object to guess and find the appropriate environment variable to use for the decoding.
 // Once the environment variable matching the field looked is found, it will unmarshall the value and the set the field with it.
 func (l *Lamenv) Unmarshal(object interface{}, parts []string) error {
-	return l.decode(reflect.ValueOf(object), parts)
+	return l.decode(reflect.ValueOf(object), parts, fieldOptions{})
 }
 
 // AddTagSupport modify the current tag list supported by adding the one passed as a parameter.
@@ -105,7 +210,7 @@ func (l *Lamenv) OverrideTagSupport(tags ...string) *Lamenv {
 	return l
 }
 
-func (l *Lamenv) decode(conf reflect.Value, parts []string) error {
+func (l *Lamenv) decode(conf reflect.Value, parts []string, opts fieldOptions) error {
 	v := conf
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
@@ -116,30 +221,56 @@ func (l *Lamenv) decode(conf reflect.Value, parts []string) error {
 		v = v.Elem()
 	}
 
+	if handled, err := l.decodeCustom(v, parts, opts); handled {
+		if err != nil {
+			return err
+		}
+		return l.validate(v, opts)
+	}
+
 	switch v.Kind() {
 	case reflect.Map:
-		if err := l.decodeMap(v, parts); err != nil {
+		if err := l.decodeMap(v, parts, opts); err != nil {
 			return err
 		}
 	case reflect.Slice:
-		if err := l.decodeSlice(v, parts); err != nil {
+		if err := l.decodeSlice(v, parts, opts); err != nil {
 			return err
 		}
 	case reflect.Struct:
-		if err := l.decodeStruct(v, parts); err != nil {
-			return err
-		}
+		var errs []error
+		errs = appendError(errs, l.decodeStruct(v, parts))
+		errs = appendError(errs, l.validate(v, opts))
+		return joinErrors(errs)
 	default:
-		if variable, input, ok := lookupEnv(parts); ok {
+		if variable, input, ok := l.lookupEnv(parts); ok {
 			// remove the variable to avoid to reuse it later
 			delete(l.env, variable)
-			return l.decodeNative(v, input)
+			if err := l.decodeNative(v, input, opts); err != nil {
+				return err
+			}
+			return l.validate(v, opts)
+		}
+		if opts.HasDefault {
+			if err := l.decodeNative(v, opts.Default, opts); err != nil {
+				return err
+			}
+			return l.validate(v, opts)
 		}
+		if opts.Required {
+			return fmt.Errorf("required environment variable %q not found", l.buildEnvVariable(parts))
+		}
+		return nil
 	}
-	return nil
+	return l.validate(v, opts)
 }
 
-func (l *Lamenv) decodeNative(v reflect.Value, input string) error {
+// decodeNative decodes input into v's native kind, then enforces opts' `min`, `max`,
+// `oneof` and `pattern` constraints, if any, against the resulting value. It errors out for
+// a kind it has no native decoding for and that didn't already go through decodeCustom
+// (typically a struct, slice or map reached while decoding a delimited slice/map token),
+// instead of silently leaving v at its zero value.
+func (l *Lamenv) decodeNative(v reflect.Value, input string, opts fieldOptions) error {
 	switch v.Kind() {
 	case reflect.String:
 		l.decodeString(v, input)
@@ -168,8 +299,10 @@ func (l *Lamenv) decodeNative(v reflect.Value, input string) error {
 		if err := l.decodeFloat(v, input); err != nil {
 			return err
 		}
+	default:
+		return fmt.Errorf("unable to unmarshal %q into a %s", input, v.Type())
 	}
-	return nil
+	return validateConstraints(v, opts, input)
 }
 
 func (l *Lamenv) decodeString(v reflect.Value, input string) {
@@ -212,18 +345,34 @@ func (l *Lamenv) decodeFloat(v reflect.Value, input string) error {
 	return nil
 }
 
-// decodeSlice will support ony one syntax which is:
-//        <PREFIX>_<SLICE_INDEX>(_<SUFFIX>)?
-// This syntax is the only one that is able to manage smoothly every existing type in Golang and it is a determinist syntax.
-func (l *Lamenv) decodeSlice(v reflect.Value, parts []string) error {
+// decodeSlice supports two syntaxes:
+//   - the indexed one, <PREFIX>_<SLICE_INDEX>(_<SUFFIX>)?, which is able to manage smoothly
+//     every existing type in Golang and is a determinist syntax.
+//   - the delimited one, a single <PREFIX> variable holding every value separated by
+//     ListSeparator (or the field's `lamenv:"separator=..."` override), e.g. "rob,ken,robert".
+//     It only applies when the variable actually exists; otherwise the indexed syntax is used.
+//
+// It's an error for both forms to be set for the same field at once: there would be no
+// sound way to tell which one the caller actually meant, and silently picking one would
+// mean silently ignoring the other.
+func (l *Lamenv) decodeSlice(v reflect.Value, parts []string, opts fieldOptions) error {
+	if separator, ok := l.effectiveSeparator(opts); ok {
+		if variable, input, found := l.lookupEnv(parts); found {
+			if l.hasIndexedSibling(variable) {
+				return fmt.Errorf("both a delimited value and an indexed one are set for %q: use only one of the two forms", variable)
+			}
+			delete(l.env, variable)
+			return l.decodeDelimitedSlice(v, input, separator, opts)
+		}
+	}
 	sliceType := v.Type().Elem()
 	// While we are able to find an environment variable that is starting by <PREFIX>_<SLICE_INDEX>
 	// then it will create a new item in a slice and will use the next recursive loop to set it.
 	i := 0
-	for ok := contains(append(parts, strconv.Itoa(i))); ok; ok = contains(append(parts, strconv.Itoa(i))) {
+	for ok := l.contains(append(parts, strconv.Itoa(i))); ok; ok = l.contains(append(parts, strconv.Itoa(i))) {
 		// create a new item and pass it to the method decode to be able to "decode" its value
 		tmp := reflect.Indirect(reflect.New(sliceType))
-		if err := l.decode(tmp, append(parts, strconv.Itoa(i))); err != nil {
+		if err := l.decode(tmp, append(parts, strconv.Itoa(i)), opts.forElement()); err != nil {
 			return err
 		}
 		v.Set(reflect.Append(v, tmp))
@@ -232,7 +381,30 @@ func (l *Lamenv) decodeSlice(v reflect.Value, parts []string) error {
 	return nil
 }
 
+// decodeDelimitedSlice splits input on separator and decodes each resulting token into a
+// new element of v, which must be addressable to the element's kind (native or custom). opts
+// is the slice field's own options; only its min/max/oneof/pattern constraints (opts.forElement)
+// carry over to every element, exactly like the indexed convention applies them to each
+// <PREFIX>_<SLICE_INDEX> variable — its separator/keyval directives don't, so they stay
+// available to an element that is itself a delimited-capable slice or map.
+func (l *Lamenv) decodeDelimitedSlice(v reflect.Value, input string, separator string, opts fieldOptions) error {
+	sliceType := v.Type().Elem()
+	result := reflect.MakeSlice(v.Type(), 0, 0)
+	if strings.TrimSpace(input) != "" {
+		for _, token := range strings.Split(input, separator) {
+			tmp := reflect.Indirect(reflect.New(sliceType))
+			if err := l.decodeValue(tmp, strings.TrimSpace(token), opts.forElement()); err != nil {
+				return err
+			}
+			result = reflect.Append(result, tmp)
+		}
+	}
+	v.Set(result)
+	return nil
+}
+
 func (l *Lamenv) decodeStruct(v reflect.Value, parts []string) error {
+	var errs []error
 	for i := 0; i < v.NumField(); i++ {
 		attr := v.Field(i)
 		if !attr.CanSet() {
@@ -240,15 +412,14 @@ func (l *Lamenv) decodeStruct(v reflect.Value, parts []string) error {
 			continue
 		}
 		attrField := v.Type().Field(i)
+		opts := parseFieldOptions(attrField.Tag)
 		attrName, ok := l.lookupTag(attrField.Tag)
 		if ok {
 			if attrName == "-" {
 				continue
 			}
 			if attrName == ",squash" || attrName == ",inline" {
-				if err := l.decode(attr, parts); err != nil {
-					return err
-				}
+				errs = appendError(errs, l.decode(attr, parts, fieldOptions{}))
 				continue
 			}
 			if strings.Contains(attrName, "omitempty") {
@@ -258,21 +429,22 @@ func (l *Lamenv) decodeStruct(v reflect.Value, parts []string) error {
 				// To be more accurate, we would have to check the type of the field, because if it's a native type, then we will have to check if the parts are matching an environment variable.
 				// If it's a struct or an array or a map, then we will have to check if there is at least one variable starting by the parts + "_" (which would remove the possibility of having a field being a prefix of another one)
 				// So it's simpler like that. Let's see if I'm wrong or not.
-				if !contains(append(parts, attrName)) {
+				if !l.contains(append(parts, attrName)) && !opts.HasDefault && !opts.Required {
 					continue
 				}
 			}
 		} else {
 			attrName = attrField.Name
+			if opts.SplitWords {
+				attrName = splitWords(attrName)
+			}
 		}
-		if err := l.decode(attr, append(parts, attrName)); err != nil {
-			return err
-		}
+		errs = appendError(errs, l.decode(attr, append(parts, attrName), opts))
 	}
-	return nil
+	return joinErrors(errs)
 }
 
-func (l *Lamenv) decodeMap(v reflect.Value, parts []string) error {
+func (l *Lamenv) decodeMap(v reflect.Value, parts []string, opts fieldOptions) error {
 	keyType := v.Type().Key()
 	valueType := v.Type().Elem()
 	if keyType.Kind() != reflect.String {
@@ -281,6 +453,17 @@ func (l *Lamenv) decodeMap(v reflect.Value, parts []string) error {
 	if valueType.Kind() == reflect.Map {
 		return fmt.Errorf("unable to unmarshal a map of a map, it's not a determinist datamodel")
 	}
+	if separator, ok := l.effectiveSeparator(opts); ok {
+		if kvSeparator, ok := l.effectiveKeyValSeparator(opts); ok {
+			if variable, input, found := l.lookupEnv(parts); found {
+				if l.hasIndexedSibling(variable) {
+					return fmt.Errorf("both a delimited value and an individual entry are set for %q: use only one of the two forms", variable)
+				}
+				delete(l.env, variable)
+				return l.decodeDelimitedMap(v, input, separator, kvSeparator, opts)
+			}
+		}
+	}
 	valMap := v
 	if v.IsNil() {
 		mapType := reflect.MapOf(keyType, valueType)
@@ -292,20 +475,20 @@ func (l *Lamenv) decodeMap(v reflect.Value, parts []string) error {
 	// Like that we are able catch the key that would be in the middle of the prefix parts and the future parts
 
 	// Let's create first the struct that would represent what is behind the value of the map
-	parser := newRing(valueType, l.TagSupports)
+	parser := newRing(valueType, l.TagSupports, l.CaseSensitive)
 
 	// then foreach environment variable:
 	// 1. Remove the prefix parts
 	// 2. Pass the remaining parts to the parser that would return the prefix to be used.
 	for e := range l.env {
-		variable := buildEnvVariable(parts)
-		trimEnv := strings.TrimPrefix(e, variable+"_")
+		variable := l.buildEnvVariable(parts)
+		trimEnv := strings.TrimPrefix(e, variable+l.wordSeparator())
 		if trimEnv == e {
 			// TrimPrefix didn't remove anything, so that means, the environment variable doesn't start with the prefix parts
 			continue
 		}
-		futureParts := strings.Split(trimEnv, "_")
-		prefix, err := guessPrefix(futureParts, parser)
+		futureParts := strings.Split(trimEnv, l.wordSeparator())
+		prefix, err := guessPrefix(futureParts, parser, l.wordSeparator())
 		if err != nil {
 			return err
 		}
@@ -313,13 +496,16 @@ func (l *Lamenv) decodeMap(v reflect.Value, parts []string) error {
 			// no prefix find, let's move to the next environment
 			continue
 		}
-		keyString := strings.ToLower(prefix)
+		keyString := prefix
+		if !l.CaseSensitive {
+			keyString = strings.ToLower(keyString)
+		}
 		value := reflect.Indirect(reflect.New(valueType))
-		if err := l.decode(value, append(parts, keyString)); err != nil {
+		if err := l.decode(value, append(parts, keyString), opts.forElement()); err != nil {
 			return err
 		}
 		key := reflect.Indirect(reflect.New(reflect.TypeOf("")))
-		key.SetString(strings.TrimSpace(strings.ToLower(keyString)))
+		key.SetString(strings.TrimSpace(keyString))
 		valMap.SetMapIndex(key, value)
 	}
 	// Set the built up map to the value
@@ -327,18 +513,101 @@ func (l *Lamenv) decodeMap(v reflect.Value, parts []string) error {
 	return nil
 }
 
+// decodeDelimitedMap splits input on separator into entries, then splits each entry on
+// kvSeparator into a key and a value, which is decoded into a new map entry. opts is the map
+// field's own options; only its min/max/oneof/pattern constraints (opts.forElement) carry
+// over to every value, exactly like the indexed/guessed-prefix convention applies them to
+// each entry — its separator/keyval directives don't, so they stay available to a value
+// that is itself a delimited-capable slice or map.
+func (l *Lamenv) decodeDelimitedMap(v reflect.Value, input string, separator string, kvSeparator string, opts fieldOptions) error {
+	mapType := v.Type()
+	valMap := reflect.MakeMap(mapType)
+	if strings.TrimSpace(input) != "" {
+		for _, entry := range strings.Split(input, separator) {
+			kv := strings.SplitN(entry, kvSeparator, 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("%q is not a valid %q separated key/value pair", entry, kvSeparator)
+			}
+			value := reflect.Indirect(reflect.New(mapType.Elem()))
+			if err := l.decodeValue(value, strings.TrimSpace(kv[1]), opts.forElement()); err != nil {
+				return err
+			}
+			key := reflect.Indirect(reflect.New(mapType.Key()))
+			key.SetString(strings.TrimSpace(kv[0]))
+			valMap.SetMapIndex(key, value)
+		}
+	}
+	v.Set(valMap)
+	return nil
+}
+
+// effectiveSeparator returns the delimiter to use for decoding a delimited slice/map, giving
+// precedence to the field's `lamenv:"separator=..."` tag over the Lamenv.ListSeparator default.
+// It returns false when neither is set, meaning the delimited syntax doesn't apply to the field.
+func (l *Lamenv) effectiveSeparator(opts fieldOptions) (string, bool) {
+	if opts.HasSeparator {
+		return opts.Separator, opts.Separator != ""
+	}
+	return l.ListSeparator, l.ListSeparator != ""
+}
+
+// effectiveKeyValSeparator is the map counterpart of effectiveSeparator for the
+// `lamenv:"keyval=..."` tag and the Lamenv.MapKVSeparator default.
+func (l *Lamenv) effectiveKeyValSeparator(opts fieldOptions) (string, bool) {
+	if opts.HasKeyValSeparator {
+		return opts.KeyValSeparator, opts.KeyValSeparator != ""
+	}
+	return l.MapKVSeparator, l.MapKVSeparator != ""
+}
+
+// decodeValue decodes input directly into v, trying the custom decoders (Decoder,
+// encoding.TextUnmarshaler, encoding.BinaryUnmarshaler, RegisterDecoder) before falling
+// back to the generic kind based decoding, then enforces opts' `min`/`max`/`oneof`/`pattern`
+// constraints and runs the Validator hooks, exactly like decode does for a field backed by
+// its own environment variable. Unlike decode/decodeCustom it isn't backed by one, which is
+// why it's used to decode the tokens of a delimited slice or map.
+func (l *Lamenv) decodeValue(v reflect.Value, input string, opts fieldOptions) error {
+	if handled, err := l.decodeCustomValue(v, input); handled {
+		if err != nil {
+			return err
+		}
+		if err := validateConstraints(v, opts, input); err != nil {
+			return err
+		}
+		return l.validate(v, opts)
+	}
+	if err := l.decodeNative(v, input, opts); err != nil {
+		return err
+	}
+	return l.validate(v, opts)
+}
+
 func (l *Lamenv) lookupTag(tag reflect.StructTag) (string, bool) {
 	return lookupTag(tag, l.TagSupports)
 }
 
-func contains(parts []string) bool {
-	variable := buildEnvVariable(parts)
-	for _, e := range os.Environ() {
-		envSplit := strings.Split(e, "=")
-		if len(envSplit) != 2 {
-			continue
+// contains tells whether any variable exposed by l.sources and not yet consumed by the decoding
+// contains variable (built from parts) as a substring of its name. It's used to probe for the next indexed slice element
+// (<PREFIX>_<INDEX>) without knowing its exact suffix.
+func (l *Lamenv) contains(parts []string) bool {
+	variable := l.buildEnvVariable(parts)
+	for e := range l.env {
+		if strings.Contains(e, variable) {
+			return true
 		}
-		if strings.Contains(envSplit[0], variable) {
+	}
+	return false
+}
+
+// hasIndexedSibling tells whether any variable other than variable itself is named
+// variable, followed by a word separator, followed by anything (e.g. "variable_0" or
+// "variable_red"). It's used to reject a delimited slice/map value when the
+// indexed/prefixed convention is also in use for the same field, instead of silently
+// picking one and ignoring the other.
+func (l *Lamenv) hasIndexedSibling(variable string) bool {
+	prefix := variable + l.wordSeparator()
+	for e := range l.env {
+		if strings.HasPrefix(e, prefix) {
 			return true
 		}
 	}
@@ -349,9 +618,9 @@ func contains(parts []string) bool {
 // 1. the name of the environment variable
 // 2. the value of the environment variable
 // 3. if the environment variable exists
-func lookupEnv(parts []string) (string, string, bool) {
-	variable := buildEnvVariable(parts)
-	value, ok := os.LookupEnv(variable)
+func (l *Lamenv) lookupEnv(parts []string) (string, string, bool) {
+	variable := l.buildEnvVariable(parts)
+	value, ok := MultiSource(l.sources).Lookup(variable)
 	return variable, value, ok
 }
 
@@ -364,10 +633,30 @@ func lookupTag(tag reflect.StructTag, tagSupports []string) (string, bool) {
 	return "", false
 }
 
-func buildEnvVariable(parts []string) string {
+// buildEnvVariable builds the environment variable name matching parts. By default, each
+// part is uppercased and joined with a single "_", which is ambiguous between a nesting
+// boundary and an underscore inside a field name (e.g. a `yaml:"foo_bar"` tag): both
+// "MY_PREFIX_FOO_BAR" forms look the same whichever field produced them. Setting
+// WordSeparator (conventionally "__") lifts that ambiguity by using it only between parts,
+// so a prefix "MY_PREFIX" and a field name "foo_bar" unambiguously build "MY_PREFIX__FOO_BAR".
+// CaseSensitive opts out of the uppercasing, for sources whose keys aren't all-caps.
+func (l *Lamenv) buildEnvVariable(parts []string) string {
 	newParts := make([]string, len(parts))
 	for i, s := range parts {
-		newParts[i] = strings.ToUpper(s)
+		if l.CaseSensitive {
+			newParts[i] = s
+		} else {
+			newParts[i] = strings.ToUpper(s)
+		}
+	}
+	return strings.Join(newParts, l.wordSeparator())
+}
+
+// wordSeparator is the delimiter buildEnvVariable joins parts with: WordSeparator if set,
+// "_" otherwise, which keeps the default behavior backward compatible.
+func (l *Lamenv) wordSeparator() string {
+	if l.WordSeparator != "" {
+		return l.WordSeparator
 	}
-	return strings.Join(newParts, "_")
+	return "_"
 }
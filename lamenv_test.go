@@ -0,0 +1,258 @@
+package lamenv
+
+import (
+	"testing"
+)
+
+func TestUnmarshal_DefaultRequiredSplitWords(t *testing.T) {
+	type Config struct {
+		Name     string `default:"anonymous"`
+		APIKey   string `split_words:"true" required:"true"`
+		Optional string
+	}
+
+	t.Run("default is used when missing", func(t *testing.T) {
+		l := New().WithSource(MapSource{"API_KEY": "secret"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.Name != "anonymous" {
+			t.Errorf("Name = %q, want %q", c.Name, "anonymous")
+		}
+		if c.APIKey != "secret" {
+			t.Errorf("APIKey = %q, want %q", c.APIKey, "secret")
+		}
+	})
+
+	t.Run("required reports a missing variable", func(t *testing.T) {
+		l := New().WithSource(MapSource{})
+		var c Config
+		err := l.Unmarshal(&c, nil)
+		if err == nil {
+			t.Fatal("expected an error for the missing required API_KEY")
+		}
+	})
+
+	t.Run("required errors are aggregated", func(t *testing.T) {
+		type TwoRequired struct {
+			A string `required:"true"`
+			B string `required:"true"`
+		}
+		l := New().WithSource(MapSource{})
+		var c TwoRequired
+		err := l.Unmarshal(&c, nil)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if _, ok := err.(multiError); !ok {
+			t.Fatalf("expected a multiError aggregating both fields, got %T: %v", err, err)
+		}
+	})
+}
+
+func TestUnmarshal_SliceIndexedVsDelimited(t *testing.T) {
+	type Config struct {
+		Users []string `lamenv:"separator=,"`
+	}
+
+	t.Run("indexed convention", func(t *testing.T) {
+		l := New().WithSource(MapSource{"USERS_0": "rob", "USERS_1": "ken"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"rob", "ken"}
+		if len(c.Users) != len(want) || c.Users[0] != want[0] || c.Users[1] != want[1] {
+			t.Errorf("Users = %v, want %v", c.Users, want)
+		}
+	})
+
+	t.Run("delimited convention", func(t *testing.T) {
+		l := New().WithSource(MapSource{"USERS": "rob,ken"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"rob", "ken"}
+		if len(c.Users) != len(want) || c.Users[0] != want[0] || c.Users[1] != want[1] {
+			t.Errorf("Users = %v, want %v", c.Users, want)
+		}
+	})
+
+	t.Run("both set at once is an error", func(t *testing.T) {
+		l := New().WithSource(MapSource{"USERS": "rob,ken", "USERS_0": "robert"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatal("expected an error when both the delimited and indexed forms are set")
+		}
+	})
+}
+
+func TestUnmarshal_MapIndexedVsDelimited(t *testing.T) {
+	type Person struct {
+		Name string
+		Age  int
+	}
+	type Config struct {
+		Counts map[string]int
+		People map[string]Person
+		Pairs  map[string]string `lamenv:"separator=,;keyval=:"`
+	}
+
+	l := New().WithSource(MapSource{
+		"COUNTS_RED":      "1",
+		"COUNTS_GREEN":    "2",
+		"PEOPLE_ROB_NAME": "Rob",
+		"PEOPLE_ROB_AGE":  "30",
+		"PEOPLE_KEN_NAME": "Ken",
+		"PEOPLE_KEN_AGE":  "40",
+		"PAIRS":           "a:1,b:2",
+	})
+	var c Config
+	if err := l.Unmarshal(&c, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Counts["red"] != 1 || c.Counts["green"] != 2 {
+		t.Errorf("Counts = %v", c.Counts)
+	}
+	if c.People["rob"] != (Person{Name: "Rob", Age: 30}) {
+		t.Errorf("People[rob] = %+v", c.People["rob"])
+	}
+	if c.People["ken"] != (Person{Name: "Ken", Age: 40}) {
+		t.Errorf("People[ken] = %+v", c.People["ken"])
+	}
+	if c.Pairs["a"] != "1" || c.Pairs["b"] != "2" {
+		t.Errorf("Pairs = %v", c.Pairs)
+	}
+}
+
+func TestUnmarshal_DelimitedElementOfUnsupportedKindIsAnError(t *testing.T) {
+	type Person struct {
+		Name string
+	}
+
+	t.Run("delimited slice of structs", func(t *testing.T) {
+		type Config struct {
+			People []Person `lamenv:"separator=,"`
+		}
+		l := New().WithSource(MapSource{"PEOPLE": "a,b"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatalf("expected an error, got Unmarshal into %#v", c.People)
+		}
+	})
+
+	t.Run("delimited map of structs", func(t *testing.T) {
+		type Config struct {
+			People map[string]Person `lamenv:"separator=,;keyval=:"`
+		}
+		l := New().WithSource(MapSource{"PEOPLE": "rob:a,ken:b"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatalf("expected an error, got Unmarshal into %#v", c.People)
+		}
+	})
+}
+
+func TestUnmarshal_NestedSliceKeepsItsOwnSeparator(t *testing.T) {
+	// A slice-of-slices field's own `separator` tag must not leak into each element's own
+	// delimited decoding: Lists uses "|" as its own separator, but each inner []string
+	// element still uses the default ListSeparator.
+	type Config struct {
+		Lists [][]string `lamenv:"separator=|"`
+	}
+	l := New().WithSource(MapSource{"LISTS_0": "a,b,c", "LISTS_1": "d,e"})
+	l.ListSeparator = ","
+	var c Config
+	if err := l.Unmarshal(&c, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]string{{"a", "b", "c"}, {"d", "e"}}
+	if len(c.Lists) != len(want) {
+		t.Fatalf("Lists = %v, want %v", c.Lists, want)
+	}
+	for i := range want {
+		if len(c.Lists[i]) != len(want[i]) {
+			t.Fatalf("Lists[%d] = %v, want %v", i, c.Lists[i], want[i])
+		}
+		for j := range want[i] {
+			if c.Lists[i][j] != want[i][j] {
+				t.Errorf("Lists[%d][%d] = %q, want %q", i, j, c.Lists[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+func TestUnmarshal_MapBothFormsIsAnError(t *testing.T) {
+	type Config struct {
+		Pairs map[string]string `lamenv:"separator=,;keyval=:"`
+	}
+	l := New().WithSource(MapSource{"PAIRS": "a:1", "PAIRS_B": "2"})
+	var c Config
+	if err := l.Unmarshal(&c, nil); err == nil {
+		t.Fatal("expected an error when both the delimited and individual-entry forms are set")
+	}
+}
+
+func TestUnmarshal_WordSeparator(t *testing.T) {
+	type Nested struct {
+		FooBar string `yaml:"foo_bar"`
+	}
+	type Config struct {
+		Nested Nested
+	}
+
+	t.Run("ambiguous with the default single underscore", func(t *testing.T) {
+		l := New().WithSource(MapSource{"NESTED_FOO_BAR": "value"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.Nested.FooBar != "value" {
+			t.Errorf("FooBar = %q, want %q", c.Nested.FooBar, "value")
+		}
+	})
+
+	t.Run("double underscore lifts the ambiguity", func(t *testing.T) {
+		l := New().WithSource(MapSource{"NESTED__FOO_BAR": "value"})
+		l.WordSeparator = "__"
+		var c Config
+		if err := l.Unmarshal(&c, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.Nested.FooBar != "value" {
+			t.Errorf("FooBar = %q, want %q", c.Nested.FooBar, "value")
+		}
+	})
+}
+
+func TestUnmarshal_CaseSensitive(t *testing.T) {
+	type Config struct {
+		Name string
+	}
+	l := New().WithSource(MapSource{"Name": "value"})
+	l.CaseSensitive = true
+	var c Config
+	if err := l.Unmarshal(&c, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Name != "value" {
+		t.Errorf("Name = %q, want %q", c.Name, "value")
+	}
+}
+
+func TestUnmarshal_CaseSensitiveMapKeysPreserveCase(t *testing.T) {
+	type Config struct {
+		Tags map[string]string
+	}
+	l := New().WithSource(MapSource{"Tags_FooBar": "value"})
+	l.CaseSensitive = true
+	var c Config
+	if err := l.Unmarshal(&c, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := c.Tags["FooBar"]; !ok || v != "value" {
+		t.Errorf("Tags = %v, want {\"FooBar\": \"value\"}", c.Tags)
+	}
+}
@@ -0,0 +1,295 @@
+package lamenv
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Marshal walks object, which must be a struct, map or a pointer to one, and flattens it
+// into the same environment variable namespace Unmarshal would consume to rebuild it: one
+// key per leaf field, uppercased and joined with "_", following the "json"/"yaml"/"mapstructure"
+// tag precedence handled by lookupTag, `split_words`, `squash`/`inline`, and skipping fields
+// tagged `omitempty` when they hold their zero value, the same way decodeStruct reads them.
+// The parts can be used to inject a prefix of the environment variable, exactly like Unmarshal.
+//
+// A field whose type implements encoding.TextMarshaler or encoding.BinaryMarshaler (in that
+// order of precedence), or whose type has been registered through Lamenv.RegisterEncoder, is
+// rendered using it instead of the generic kind based encoding, mirroring how Unmarshal
+// decodes it. time.Duration, time.Time, net.IP, url.URL and []byte are supported out of the
+// box this way.
+//
+// Marshal is the mirror of Unmarshal: a round trip through Marshal then Unmarshal yields back
+// the original structure. It's useful to propagate configuration to a child process via
+// exec.Command{Env: ...}, or to render a .env file through MarshalEnv.
+func Marshal(object interface{}, parts []string) (map[string]string, error) {
+	return New().Marshal(object, parts)
+}
+
+// MarshalEnv is Marshal followed by a deterministic rendering of the resulting map as
+// sorted "KEY=VALUE" lines, ready to be used as a process environment or written to a .env file.
+func MarshalEnv(object interface{}, parts []string) ([]string, error) {
+	return New().MarshalEnv(object, parts)
+}
+
+// RegisterEncoder associates a function able to render a value of type t as the string that
+// would go into its environment variable. It's the Marshal counterpart of RegisterDecoder,
+// and behaves the same way: calling RegisterEncoder again for a type
+// already registered overrides the previous one, which is also how the built-in encoders
+// (time.Duration, []byte) can be replaced if the default behavior doesn't fit.
+func (l *Lamenv) RegisterEncoder(t reflect.Type, encode func(interface{}) (string, error)) *Lamenv {
+	l.encoders[t] = encode
+	return l
+}
+
+// registerBuiltinEncoders wires up the encoders for the stdlib types lamenv supports out of
+// the box and that do not already implement encoding.TextMarshaler or
+// encoding.BinaryMarshaler: encodeCustom checks those interfaces before falling back to this
+// registry, so registering a type that implements either here would only ever be dead code.
+// time.Time, net.IP and url.URL are notably absent for that reason, mirroring
+// registerBuiltinDecoders.
+func registerBuiltinEncoders(encoders map[reflect.Type]func(interface{}) (string, error)) {
+	encoders[reflect.TypeOf(time.Duration(0))] = func(value interface{}) (string, error) {
+		return value.(time.Duration).String(), nil
+	}
+	encoders[reflect.TypeOf([]byte(nil))] = func(value interface{}) (string, error) {
+		return base64.StdEncoding.EncodeToString(value.([]byte)), nil
+	}
+}
+
+// encodeCustom looks for a way to encode v that bypasses the generic kind-based encoding: an
+// encoding.TextMarshaler, an encoding.BinaryMarshaler, or an encoder registered through
+// RegisterEncoder, in that order of precedence, mirroring decodeCustomValue. It returns true
+// when one of them has taken over the encoding.
+func (l *Lamenv) encodeCustom(v reflect.Value) (string, bool, error) {
+	if !v.IsValid() {
+		return "", false, nil
+	}
+	if !v.CanAddr() {
+		// map values, or a struct passed by value: work on an addressable copy so that
+		// methods declared on the pointer receiver are found too
+		tmp := reflect.New(v.Type()).Elem()
+		tmp.Set(v)
+		v = tmp
+	}
+	ptr := v.Addr().Interface()
+	encodeFn, hasEncodeFn := l.encoders[v.Type()]
+
+	textEnc, isTextEnc := ptr.(encoding.TextMarshaler)
+	binEnc, isBinEnc := ptr.(encoding.BinaryMarshaler)
+	if !isTextEnc && !isBinEnc && !hasEncodeFn {
+		return "", false, nil
+	}
+
+	switch {
+	case isTextEnc:
+		data, err := textEnc.MarshalText()
+		return string(data), true, err
+	case isBinEnc:
+		data, err := binEnc.MarshalBinary()
+		return string(data), true, err
+	default:
+		str, err := encodeFn(v.Interface())
+		return str, true, err
+	}
+}
+
+// Marshal is the method counterpart of the package-level Marshal, using l's TagSupports and
+// ListSeparator/MapKVSeparator to decide which environment variable name and string
+// representation to use for each field.
+func (l *Lamenv) Marshal(object interface{}, parts []string) (map[string]string, error) {
+	result := make(map[string]string)
+	if err := l.encode(reflect.ValueOf(object), parts, fieldOptions{}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MarshalEnv is the method counterpart of the package-level MarshalEnv.
+func (l *Lamenv) MarshalEnv(object interface{}, parts []string) ([]string, error) {
+	m, err := l.Marshal(object, parts)
+	if err != nil {
+		return nil, err
+	}
+	return formatEnv(m), nil
+}
+
+// formatEnv renders m as "KEY=VALUE" lines sorted by key, for a deterministic output.
+func formatEnv(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s=%s", k, m[k]))
+	}
+	return lines
+}
+
+func (l *Lamenv) encode(conf reflect.Value, parts []string, opts fieldOptions, result map[string]string) error {
+	v := conf
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			// nothing to marshal behind an uninitialized pointer
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if str, handled, err := l.encodeCustom(v); handled {
+		if err != nil {
+			return err
+		}
+		result[l.buildEnvVariable(parts)] = str
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		return l.encodeMap(v, parts, opts, result)
+	case reflect.Slice:
+		return l.encodeSlice(v, parts, opts, result)
+	case reflect.Struct:
+		return l.encodeStruct(v, parts, result)
+	default:
+		if str, ok := l.encodeNative(v); ok {
+			result[l.buildEnvVariable(parts)] = str
+		}
+	}
+	return nil
+}
+
+func (l *Lamenv) encodeNative(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32,
+		reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// encodeSlice is the mirror of decodeSlice: it uses the delimited syntax when a separator
+// applies to the field, and falls back to the indexed <PREFIX>_<SLICE_INDEX>(_<SUFFIX>)? one
+// otherwise.
+func (l *Lamenv) encodeSlice(v reflect.Value, parts []string, opts fieldOptions, result map[string]string) error {
+	if separator, ok := l.effectiveSeparator(opts); ok {
+		tokens := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			str, handled, err := l.encodeCustom(v.Index(i))
+			if err != nil {
+				return err
+			}
+			if !handled {
+				if str, handled = l.encodeNative(v.Index(i)); !handled {
+					return fmt.Errorf("unable to marshal a delimited slice of %s", v.Type().Elem())
+				}
+			}
+			tokens = append(tokens, str)
+		}
+		result[l.buildEnvVariable(parts)] = strings.Join(tokens, separator)
+		return nil
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := l.encode(v.Index(i), append(parts, strconv.Itoa(i)), fieldOptions{}, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeStruct is the mirror of decodeStruct: for every exported field it figures out the
+// same environment variable name decodeStruct would look up for it, and recurses into encode.
+func (l *Lamenv) encodeStruct(v reflect.Value, parts []string, result map[string]string) error {
+	for i := 0; i < v.NumField(); i++ {
+		attr := v.Field(i)
+		attrField := v.Type().Field(i)
+		if attrField.PkgPath != "" {
+			// the field is not exported, so it wouldn't have been set by Unmarshal either.
+			continue
+		}
+		opts := parseFieldOptions(attrField.Tag)
+		attrName, ok := l.lookupTag(attrField.Tag)
+		if ok {
+			if attrName == "-" {
+				continue
+			}
+			if attrName == ",squash" || attrName == ",inline" {
+				if err := l.encode(attr, parts, fieldOptions{}, result); err != nil {
+					return err
+				}
+				continue
+			}
+			if strings.Contains(attrName, "omitempty") && attr.IsZero() {
+				continue
+			}
+		} else {
+			attrName = attrField.Name
+			if opts.SplitWords {
+				attrName = splitWords(attrName)
+			}
+		}
+		if err := l.encode(attr, append(parts, attrName), opts, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeMap is the mirror of decodeMap: it uses the delimited syntax when a separator and a
+// key/value separator both apply to the field, and falls back to one environment variable per
+// map entry, keyed by <PREFIX>_<KEY>, otherwise.
+func (l *Lamenv) encodeMap(v reflect.Value, parts []string, opts fieldOptions, result map[string]string) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("unable to marshal a map with a key that is not a string")
+	}
+	if separator, ok := l.effectiveSeparator(opts); ok {
+		if kvSeparator, ok := l.effectiveKeyValSeparator(opts); ok {
+			entries := make([]string, 0, v.Len())
+			for _, key := range v.MapKeys() {
+				str, handled, err := l.encodeCustom(v.MapIndex(key))
+				if err != nil {
+					return err
+				}
+				if !handled {
+					if str, handled = l.encodeNative(v.MapIndex(key)); !handled {
+						return fmt.Errorf("unable to marshal a delimited map of %s", v.Type().Elem())
+					}
+				}
+				entries = append(entries, key.String()+kvSeparator+str)
+			}
+			// sorted so that the rendering of a given map is deterministic across calls
+			sort.Strings(entries)
+			result[l.buildEnvVariable(parts)] = strings.Join(entries, separator)
+			return nil
+		}
+	}
+	for _, key := range v.MapKeys() {
+		if err := l.encode(v.MapIndex(key), append(parts, key.String()), fieldOptions{}, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
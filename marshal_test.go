@@ -0,0 +1,107 @@
+package lamenv
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	type Person struct {
+		Name string
+		Age  int
+	}
+	type Config struct {
+		Name    string
+		Timeout time.Duration
+		When    time.Time
+		IP      net.IP
+		Site    url.URL
+		Blob    []byte
+		Tags    []string          `lamenv:"separator=,"`
+		People  map[string]Person `lamenv:"separator=,;keyval=:"`
+		Counts  map[string]int
+	}
+
+	original := Config{
+		Name:    "app",
+		Timeout: 30 * time.Second,
+		When:    time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC),
+		IP:      net.ParseIP("192.168.1.1"),
+		Site:    url.URL{Scheme: "https", Host: "example.com", Path: "/path"},
+		Blob:    []byte("hello"),
+		Tags:    []string{"a", "b"},
+		Counts:  map[string]int{"red": 1, "green": 2},
+	}
+
+	env, err := Marshal(&original, nil)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	l := New().WithSource(MapSource(env))
+	var decoded Config
+	if err := l.Unmarshal(&decoded, nil); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if decoded.Name != original.Name {
+		t.Errorf("Name = %q, want %q", decoded.Name, original.Name)
+	}
+	if decoded.Timeout != original.Timeout {
+		t.Errorf("Timeout = %v, want %v", decoded.Timeout, original.Timeout)
+	}
+	if !decoded.When.Equal(original.When) {
+		t.Errorf("When = %v, want %v", decoded.When, original.When)
+	}
+	if !decoded.IP.Equal(original.IP) {
+		t.Errorf("IP = %v, want %v", decoded.IP, original.IP)
+	}
+	if decoded.Site.String() != original.Site.String() {
+		t.Errorf("Site = %v, want %v", decoded.Site.String(), original.Site.String())
+	}
+	if string(decoded.Blob) != string(original.Blob) {
+		t.Errorf("Blob = %q, want %q", decoded.Blob, original.Blob)
+	}
+	if len(decoded.Tags) != 2 || decoded.Tags[0] != "a" || decoded.Tags[1] != "b" {
+		t.Errorf("Tags = %v, want %v", decoded.Tags, original.Tags)
+	}
+	if decoded.Counts["red"] != 1 || decoded.Counts["green"] != 2 {
+		t.Errorf("Counts = %v, want %v", decoded.Counts, original.Counts)
+	}
+}
+
+func TestMarshalEnv_Sorted(t *testing.T) {
+	type Config struct {
+		Zeta  string
+		Alpha string
+	}
+	c := Config{Zeta: "z", Alpha: "a"}
+	lines, err := MarshalEnv(&c, nil)
+	if err != nil {
+		t.Fatalf("MarshalEnv: %v", err)
+	}
+	want := []string{"ALPHA=a", "ZETA=z"}
+	if len(lines) != len(want) || lines[0] != want[0] || lines[1] != want[1] {
+		t.Errorf("lines = %v, want %v", lines, want)
+	}
+}
+
+func TestRegisterEncoder_OverridesDefault(t *testing.T) {
+	type Config struct {
+		Timeout time.Duration
+	}
+	l := New()
+	l.RegisterEncoder(reflect.TypeOf(time.Duration(0)), func(value interface{}) (string, error) {
+		return "always-this", nil
+	})
+	env, err := l.Marshal(&Config{Timeout: time.Minute}, nil)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if env["TIMEOUT"] != "always-this" {
+		t.Errorf("TIMEOUT = %q, want %q", env["TIMEOUT"], "always-this")
+	}
+}
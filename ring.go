@@ -0,0 +1,107 @@
+package lamenv
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ring describes the shape of a map's value type: every field it exposes, gathered
+// recursively the same way decodeStruct itself would walk it. decodeMap builds one once per
+// map (via newRing) and consults it (via guessPrefix) to tell the map key apart from the
+// value's own (possibly nested) field names inside a single environment variable name.
+type ring struct {
+	// paths holds every field path reachable from the value type, recursively through nested
+	// structs and `,squash`/`,inline` fields, one entry per path, itself a slice of each part's
+	// name (e.g. ["ADDRESS", "STREET"] for a nested Address.Street), cased exactly like
+	// buildEnvVariable would render it, so it can be compared against the parts of an actual
+	// environment variable name.
+	paths [][]string
+}
+
+// maxRingDepth bounds the struct walk below, so a self-referential (or merely deeply nested)
+// value type can't send newRing into an impractical recursion.
+const maxRingDepth = 8
+
+// newRing walks valueType, the element type of the map being decoded, and records the path of
+// every field it exposes, recursively, the same way decodeStruct would build it, except that
+// tag options following a comma (e.g. ",omitempty") are dropped from the name. caseSensitive
+// mirrors Lamenv.CaseSensitive, so the recorded paths are cased exactly like buildEnvVariable
+// would render them.
+func newRing(valueType reflect.Type, tagSupports []string, caseSensitive bool) *ring {
+	r := &ring{}
+	r.walk(valueType, nil, tagSupports, caseSensitive, 0)
+	return r
+}
+
+func (r *ring) walk(t reflect.Type, prefix []string, tagSupports []string, caseSensitive bool, depth int) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || depth >= maxRingDepth {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			// unexported field, decodeStruct would skip it too
+			continue
+		}
+		opts := parseFieldOptions(field.Tag)
+		name, ok := lookupTag(field.Tag, tagSupports)
+		switch {
+		case ok && name == "-":
+			continue
+		case ok && (name == ",squash" || name == ",inline"):
+			r.walk(field.Type, prefix, tagSupports, caseSensitive, depth+1)
+			continue
+		case ok:
+			name = strings.SplitN(name, ",", 2)[0]
+		default:
+			name = field.Name
+			if opts.SplitWords {
+				name = splitWords(name)
+			}
+		}
+		if !caseSensitive {
+			name = strings.ToUpper(name)
+		}
+		path := append(append([]string{}, prefix...), name)
+		r.paths = append(r.paths, path)
+		r.walk(field.Type, path, tagSupports, caseSensitive, depth+1)
+	}
+}
+
+// hasPath tells whether tail, a trailing slice of the parts of an environment variable name,
+// matches one of the field paths gathered by newRing.
+func (r *ring) hasPath(tail []string) bool {
+	for _, path := range r.paths {
+		if len(path) != len(tail) {
+			continue
+		}
+		matches := true
+		for i, part := range path {
+			if part != tail[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// guessPrefix looks, from the longest to the shortest, for a trailing sequence of futureParts
+// that matches one of parser's known field paths, and returns whatever parts are left at the
+// front, joined back with separator, as the map key. When no known field path matches any
+// trailing sequence (typically because the map's value type isn't a struct, and so has no
+// field of its own), the whole of futureParts is treated as the key.
+func guessPrefix(futureParts []string, parser *ring, separator string) (string, error) {
+	for length := 1; length < len(futureParts); length++ {
+		if parser.hasPath(futureParts[length:]) {
+			return strings.Join(futureParts[:length], separator), nil
+		}
+	}
+	return strings.Join(futureParts, separator), nil
+}
@@ -0,0 +1,77 @@
+package lamenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewRing_GathersNestedPaths(t *testing.T) {
+	type Address struct {
+		Street string
+		City   string
+	}
+	type Person struct {
+		Name    string
+		Address Address
+	}
+
+	r := newRing(reflect.TypeOf(Person{}), []string{"yaml", "json", "mapstructure"}, false)
+	want := [][]string{
+		{"NAME"},
+		{"ADDRESS"},
+		{"ADDRESS", "STREET"},
+		{"ADDRESS", "CITY"},
+	}
+	if len(r.paths) != len(want) {
+		t.Fatalf("paths = %v, want %v", r.paths, want)
+	}
+	for _, w := range want {
+		if !r.hasPath(w) {
+			t.Errorf("hasPath(%v) = false, want true", w)
+		}
+	}
+}
+
+func TestGuessPrefix_MatchesLongestKnownPath(t *testing.T) {
+	type Address struct {
+		Street string
+	}
+	type Person struct {
+		Name    string
+		Address Address
+	}
+	parser := newRing(reflect.TypeOf(Person{}), []string{"yaml", "json", "mapstructure"}, false)
+
+	prefix, err := guessPrefix([]string{"ROB", "ADDRESS", "STREET"}, parser, "_")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prefix != "ROB" {
+		t.Errorf("prefix = %q, want %q", prefix, "ROB")
+	}
+}
+
+func TestNewRing_CaseSensitivePreservesFieldCase(t *testing.T) {
+	type Address struct {
+		Street string
+	}
+	parser := newRing(reflect.TypeOf(Address{}), []string{"yaml", "json", "mapstructure"}, true)
+
+	if !parser.hasPath([]string{"Street"}) {
+		t.Errorf("hasPath([\"Street\"]) = false, want true")
+	}
+	if parser.hasPath([]string{"STREET"}) {
+		t.Errorf("hasPath([\"STREET\"]) = true, want false (case sensitive)")
+	}
+}
+
+func TestGuessPrefix_NoKnownPathUsesWholeSuffix(t *testing.T) {
+	parser := newRing(reflect.TypeOf(""), []string{"yaml", "json", "mapstructure"}, false)
+	prefix, err := guessPrefix([]string{"RED"}, parser, "_")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prefix != "RED" {
+		t.Errorf("prefix = %q, want %q", prefix, "RED")
+	}
+}
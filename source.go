@@ -0,0 +1,138 @@
+package lamenv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Source is the interface Lamenv reads environment variables from. It's implemented by
+// ProcessSource (the default, backed by os.Environ()), MapSource, the Source returned by
+// NewDotEnvSource, and MultiSource, which layers several of them together.
+type Source interface {
+	// Lookup returns the value of the variable named key, and whether it was found.
+	Lookup(key string) (string, bool)
+	// Keys returns the name of every variable exposed by the source.
+	Keys() []string
+}
+
+// ProcessSource is the Source backed by the current process environment, i.e. os.Environ()
+// and os.LookupEnv. It's the default source used by New().
+type ProcessSource struct{}
+
+// Lookup implements Source.
+func (ProcessSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// Keys implements Source.
+func (ProcessSource) Keys() []string {
+	environ := os.Environ()
+	keys := make([]string, 0, len(environ))
+	for _, e := range environ {
+		if key, _, ok := splitEnv(e); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// MapSource is the Source backed by an in-memory map, handy for tests or for values coming
+// from somewhere other than the process environment or a .env file.
+type MapSource map[string]string
+
+// Lookup implements Source.
+func (m MapSource) Lookup(key string) (string, bool) {
+	value, ok := m[key]
+	return value, ok
+}
+
+// Keys implements Source.
+func (m MapSource) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// MultiSource layers several Source together, consulting them in order and returning the
+// first match found. It's what Lamenv.WithSource builds when given more than one Source.
+type MultiSource []Source
+
+// Lookup implements Source.
+func (m MultiSource) Lookup(key string) (string, bool) {
+	for _, source := range m {
+		if value, ok := source.Lookup(key); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Keys implements Source.
+func (m MultiSource) Keys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, source := range m {
+		for _, key := range source.Keys() {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// NewDotEnvSource reads path as a .env file and returns its content as a Source.
+// The expected format is one `KEY=VALUE` directive per line:
+//   - blank lines and lines starting with "#" are ignored as comments,
+//   - a leading "export " in front of the key is tolerated and stripped,
+//   - the value can be wrapped in single or double quotes, in which case it is taken
+//     verbatim, otherwise a trailing " # ..." is treated as an inline comment.
+func NewDotEnvSource(path string) (Source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	source := make(MapSource)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		idx := strings.Index(line, "=")
+		if idx < 0 {
+			return nil, fmt.Errorf("%s:%d: missing '=' in %q", path, i+1, line)
+		}
+		key := strings.TrimSpace(line[:idx])
+		source[key] = unquoteDotEnvValue(strings.TrimSpace(line[idx+1:]))
+	}
+	return source, nil
+}
+
+// unquoteDotEnvValue strips the quotes off a quoted .env value, or trims a trailing
+// "# ..." inline comment off an unquoted one.
+func unquoteDotEnvValue(value string) string {
+	if len(value) >= 2 {
+		quote := value[0]
+		if (quote == '"' || quote == '\'') && value[len(value)-1] == quote {
+			return value[1 : len(value)-1]
+		}
+	}
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	return value
+}
+
+// splitEnv splits a "KEY=VALUE" entry as returned by os.Environ() into its key and value.
+func splitEnv(entry string) (string, string, bool) {
+	idx := strings.Index(entry, "=")
+	if idx < 0 {
+		return "", "", false
+	}
+	return entry[:idx], entry[idx+1:], true
+}
@@ -0,0 +1,109 @@
+package lamenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMapSource(t *testing.T) {
+	m := MapSource{"FOO": "bar"}
+	if v, ok := m.Lookup("FOO"); !ok || v != "bar" {
+		t.Errorf("Lookup(FOO) = %q, %v", v, ok)
+	}
+	if _, ok := m.Lookup("MISSING"); ok {
+		t.Error("Lookup(MISSING) should not be found")
+	}
+	keys := m.Keys()
+	if len(keys) != 1 || keys[0] != "FOO" {
+		t.Errorf("Keys() = %v", keys)
+	}
+}
+
+func TestMultiSource_FirstMatchWins(t *testing.T) {
+	m := MultiSource{
+		MapSource{"FOO": "first"},
+		MapSource{"FOO": "second", "BAR": "only-here"},
+	}
+	if v, ok := m.Lookup("FOO"); !ok || v != "first" {
+		t.Errorf("Lookup(FOO) = %q, %v, want %q", v, ok, "first")
+	}
+	if v, ok := m.Lookup("BAR"); !ok || v != "only-here" {
+		t.Errorf("Lookup(BAR) = %q, %v, want %q", v, ok, "only-here")
+	}
+	if _, ok := m.Lookup("MISSING"); ok {
+		t.Error("Lookup(MISSING) should not be found")
+	}
+	keys := m.Keys()
+	if len(keys) != 2 {
+		t.Errorf("Keys() = %v, want 2 deduplicated keys", keys)
+	}
+}
+
+func TestProcessSource(t *testing.T) {
+	t.Setenv("LAMENV_TEST_VAR", "hello")
+	p := ProcessSource{}
+	if v, ok := p.Lookup("LAMENV_TEST_VAR"); !ok || v != "hello" {
+		t.Errorf("Lookup(LAMENV_TEST_VAR) = %q, %v", v, ok)
+	}
+	found := false
+	for _, key := range p.Keys() {
+		if key == "LAMENV_TEST_VAR" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("Keys() did not include LAMENV_TEST_VAR")
+	}
+}
+
+func TestNewDotEnvSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	content := `# a comment
+export FOO=bar
+QUOTED="hello world"
+SINGLE='single quoted'
+WITH_COMMENT=value # trailing comment
+
+EMPTY=
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	source, err := NewDotEnvSource(path)
+	if err != nil {
+		t.Fatalf("NewDotEnvSource: %v", err)
+	}
+
+	cases := map[string]string{
+		"FOO":          "bar",
+		"QUOTED":       "hello world",
+		"SINGLE":       "single quoted",
+		"WITH_COMMENT": "value",
+		"EMPTY":        "",
+	}
+	for key, want := range cases {
+		v, ok := source.Lookup(key)
+		if !ok {
+			t.Errorf("Lookup(%s) not found", key)
+			continue
+		}
+		if v != want {
+			t.Errorf("Lookup(%s) = %q, want %q", key, v, want)
+		}
+	}
+}
+
+func TestNewDotEnvSource_MissingEquals(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("NOT_A_DIRECTIVE\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := NewDotEnvSource(path); err == nil {
+		t.Fatal("expected an error for a line missing '='")
+	}
+}
@@ -0,0 +1,141 @@
+package lamenv
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	splitWordsGatherRegexp  = regexp.MustCompile("([A-Z]+)([A-Z][a-z])")
+	splitWordsAcronymRegexp = regexp.MustCompile("([a-z0-9])([A-Z])")
+)
+
+// fieldOptions gathers the additional directives that can be set on a struct field
+// on top of the tag used to determine its environment variable name, in the spirit
+// of what envconfig and caarlos0-env are proposing.
+type fieldOptions struct {
+	// Default is the value to fall back to when no matching environment variable is found.
+	Default string
+	// HasDefault tells whether a `default` tag has been set on the field (an empty default is valid).
+	HasDefault bool
+	// Required tells lamenv to report an error when no matching environment variable
+	// is found and no default is set.
+	Required bool
+	// SplitWords tells lamenv to turn a CamelCase field name into its SNAKE_CASE
+	// equivalent when no tag is providing an explicit name (e.g. APIKey -> API_KEY).
+	SplitWords bool
+	// Separator overrides Lamenv.ListSeparator for this field: when set, a slice (or
+	// the entries of a map) can be decoded from a single delimited environment variable
+	// instead of the indexed/prefixed convention.
+	Separator string
+	// HasSeparator tells whether a `separator` directive has been set on the field
+	// (an empty separator is valid and disables the delimited syntax for the field).
+	HasSeparator bool
+	// KeyValSeparator overrides Lamenv.MapKVSeparator for this field: it separates the
+	// key from the value inside each entry of a delimited map.
+	KeyValSeparator string
+	// HasKeyValSeparator tells whether a `keyval` directive has been set on the field.
+	HasKeyValSeparator bool
+	// Min is the `min=...` directive: the minimum value for a numeric field, or minimum
+	// length (in runes) for a string field, enforced after the field is decoded.
+	Min string
+	// HasMin tells whether a `min` directive has been set on the field.
+	HasMin bool
+	// Max is the `max=...` directive, the counterpart of Min for the maximum value/length.
+	Max string
+	// HasMax tells whether a `max` directive has been set on the field.
+	HasMax bool
+	// OneOf is the `oneof=a|b|c` directive: the exhaustive list of values the decoded
+	// (trimmed) string is allowed to take.
+	OneOf []string
+	// HasOneOf tells whether a `oneof` directive has been set on the field.
+	HasOneOf bool
+	// Pattern is the `pattern=<regex>` directive: a regular expression the raw environment
+	// variable value must match.
+	Pattern string
+	// HasPattern tells whether a `pattern` directive has been set on the field.
+	HasPattern bool
+	// PatternRegexp is Pattern compiled once by parseFieldOptions, so that validateConstraints
+	// doesn't have to recompile it on every decode of the field.
+	PatternRegexp *regexp.Regexp
+	// PatternErr holds the error from compiling Pattern, surfaced by validateConstraints.
+	PatternErr error
+}
+
+// parseFieldOptions reads the `default`, `required`, `split_words` and `lamenv` tags of a
+// struct field. The `lamenv` tag holds a `;` separated list of `key=value` directives,
+// e.g. `lamenv:"separator=,;keyval=:"` or `lamenv:"min=1;max=10;oneof=a|b|c;pattern=^[a-z]+$"`.
+func parseFieldOptions(tag reflect.StructTag) fieldOptions {
+	var opts fieldOptions
+	if d, ok := tag.Lookup("default"); ok {
+		opts.Default = d
+		opts.HasDefault = true
+	}
+	if r, ok := tag.Lookup("required"); ok {
+		opts.Required = r == "true"
+	}
+	if s, ok := tag.Lookup("split_words"); ok {
+		opts.SplitWords = s == "true"
+	}
+	if lv, ok := tag.Lookup("lamenv"); ok {
+		for _, directive := range strings.Split(lv, ";") {
+			directive = strings.TrimSpace(directive)
+			kv := strings.SplitN(directive, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "separator":
+				opts.Separator = kv[1]
+				opts.HasSeparator = true
+			case "keyval":
+				opts.KeyValSeparator = kv[1]
+				opts.HasKeyValSeparator = true
+			case "min":
+				opts.Min = kv[1]
+				opts.HasMin = true
+			case "max":
+				opts.Max = kv[1]
+				opts.HasMax = true
+			case "oneof":
+				opts.OneOf = strings.Split(kv[1], "|")
+				opts.HasOneOf = true
+			case "pattern":
+				opts.Pattern = kv[1]
+				opts.HasPattern = true
+				opts.PatternRegexp, opts.PatternErr = regexp.Compile(kv[1])
+			}
+		}
+	}
+	return opts
+}
+
+// forElement returns the subset of opts that applies to a single element of a slice/map
+// field, once that field's own indexed/guessed-prefix or delimited decoding has already
+// consumed its `separator`/`keyval` directives: the `min`/`max`/`oneof`/`pattern`
+// constraints carry over to each element, but `separator`/`keyval` don't, so a field like
+// `[][]string` or `map[string][]string` doesn't have its own delimiter hijacked by the
+// outer field's.
+func (opts fieldOptions) forElement() fieldOptions {
+	return fieldOptions{
+		Min:           opts.Min,
+		HasMin:        opts.HasMin,
+		Max:           opts.Max,
+		HasMax:        opts.HasMax,
+		OneOf:         opts.OneOf,
+		HasOneOf:      opts.HasOneOf,
+		Pattern:       opts.Pattern,
+		HasPattern:    opts.HasPattern,
+		PatternRegexp: opts.PatternRegexp,
+		PatternErr:    opts.PatternErr,
+	}
+}
+
+// splitWords turns a CamelCase (or mixedCase) identifier into its SNAKE_CASE equivalent,
+// e.g. "APIKey" becomes "API_KEY" and "UserName" becomes "USER_NAME".
+func splitWords(name string) string {
+	s := splitWordsGatherRegexp.ReplaceAllString(name, "${1}_${2}")
+	s = splitWordsAcronymRegexp.ReplaceAllString(s, "${1}_${2}")
+	return strings.ToUpper(s)
+}
@@ -0,0 +1,62 @@
+package lamenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFieldOptions(t *testing.T) {
+	type Config struct {
+		WithDefault string `default:"anon"`
+		Required    string `required:"true"`
+		SplitWords  string `split_words:"true"`
+		Combined    string `lamenv:"separator=,;keyval=:;min=1;max=10;oneof=a|b;pattern=^[a-z]+$"`
+	}
+	typ := reflect.TypeOf(Config{})
+
+	opts := parseFieldOptions(typ.Field(0).Tag)
+	if !opts.HasDefault || opts.Default != "anon" {
+		t.Errorf("WithDefault opts = %+v", opts)
+	}
+
+	opts = parseFieldOptions(typ.Field(1).Tag)
+	if !opts.Required {
+		t.Errorf("Required opts = %+v", opts)
+	}
+
+	opts = parseFieldOptions(typ.Field(2).Tag)
+	if !opts.SplitWords {
+		t.Errorf("SplitWords opts = %+v", opts)
+	}
+
+	opts = parseFieldOptions(typ.Field(3).Tag)
+	if opts.Separator != "," || !opts.HasSeparator {
+		t.Errorf("Separator = %+v", opts)
+	}
+	if opts.KeyValSeparator != ":" || !opts.HasKeyValSeparator {
+		t.Errorf("KeyValSeparator = %+v", opts)
+	}
+	if opts.Min != "1" || opts.Max != "10" {
+		t.Errorf("Min/Max = %+v", opts)
+	}
+	if len(opts.OneOf) != 2 || opts.OneOf[0] != "a" || opts.OneOf[1] != "b" {
+		t.Errorf("OneOf = %v", opts.OneOf)
+	}
+	if opts.Pattern != "^[a-z]+$" || opts.PatternRegexp == nil || opts.PatternErr != nil {
+		t.Errorf("Pattern = %+v", opts)
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	cases := map[string]string{
+		"APIKey":   "API_KEY",
+		"UserName": "USER_NAME",
+		"ID":       "ID",
+		"Name":     "NAME",
+	}
+	for in, want := range cases {
+		if got := splitWords(in); got != want {
+			t.Errorf("splitWords(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
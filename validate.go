@@ -0,0 +1,123 @@
+package lamenv
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Validator is the interface a type can implement to run its own validation once lamenv has
+// finished decoding it. It's checked on every addressable value decode() populates (structs,
+// but also maps, slices and scalar fields), right after it's set, so implementing it on a
+// struct is enough to validate the whole tree in one Unmarshal call: every error returned,
+// at any depth, is aggregated into a single error the same way missing required fields are.
+//
+// A function registered through Lamenv.RegisterValidator takes precedence over this
+// interface for a given type, which is useful for types that cannot implement it themselves,
+// typically because they are coming from another package.
+type Validator interface {
+	Validate() error
+}
+
+// RegisterValidator associates a function able to validate a value of type t once it has
+// been decoded. It takes precedence over the Validator interface when t implements both.
+func (l *Lamenv) RegisterValidator(t reflect.Type, validate func(interface{}) error) *Lamenv {
+	l.validators[t] = validate
+	return l
+}
+
+// validate runs the post-decode validation hooks for v: a function registered through
+// RegisterValidator for v's type, or the Validator interface, in that order of precedence.
+func (l *Lamenv) validate(v reflect.Value, opts fieldOptions) error {
+	if !v.CanAddr() {
+		return nil
+	}
+	ptr := v.Addr().Interface()
+	if fn, ok := l.validators[v.Type()]; ok {
+		return fn(ptr)
+	}
+	if validator, ok := ptr.(Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// validateConstraints enforces the `min`, `max`, `oneof` and `pattern` directives of opts
+// against the raw input and the value it was just decoded into. It's called from
+// decodeNative, right after the value is set.
+func validateConstraints(v reflect.Value, opts fieldOptions, input string) error {
+	if opts.HasOneOf {
+		trimmed := strings.TrimSpace(input)
+		valid := false
+		for _, choice := range opts.OneOf {
+			if trimmed == choice {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("value %q is not one of %v", trimmed, opts.OneOf)
+		}
+	}
+	if opts.HasPattern {
+		if opts.PatternErr != nil {
+			return fmt.Errorf("invalid pattern %q: %w", opts.Pattern, opts.PatternErr)
+		}
+		if !opts.PatternRegexp.MatchString(input) {
+			return fmt.Errorf("value %q does not match pattern %q", input, opts.Pattern)
+		}
+	}
+	if opts.HasMin || opts.HasMax {
+		return validateMinMax(v, opts)
+	}
+	return nil
+}
+
+// validateMinMax enforces `min`/`max` as a value bound for numeric kinds, and as a rune
+// length bound for strings.
+func validateMinMax(v reflect.Value, opts fieldOptions) error {
+	what := "value"
+	var value float64
+	switch v.Kind() {
+	case reflect.String:
+		what = "length"
+		value = float64(len([]rune(v.String())))
+	case reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64:
+		value = float64(v.Int())
+	case reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64:
+		value = float64(v.Uint())
+	case reflect.Float32,
+		reflect.Float64:
+		value = v.Float()
+	default:
+		return nil
+	}
+	if opts.HasMin {
+		min, err := strconv.ParseFloat(opts.Min, 64)
+		if err != nil {
+			return fmt.Errorf("invalid min %q: %w", opts.Min, err)
+		}
+		if value < min {
+			return fmt.Errorf("%s %v is below the minimum %v", what, value, min)
+		}
+	}
+	if opts.HasMax {
+		max, err := strconv.ParseFloat(opts.Max, 64)
+		if err != nil {
+			return fmt.Errorf("invalid max %q: %w", opts.Max, err)
+		}
+		if value > max {
+			return fmt.Errorf("%s %v is above the maximum %v", what, value, max)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package lamenv
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshal_MinMaxOneOfPattern(t *testing.T) {
+	type Config struct {
+		Age  int    `lamenv:"min=0;max=120"`
+		Role string `lamenv:"oneof=admin|user"`
+		Code string `lamenv:"pattern=^[A-Z]{3}$"`
+	}
+
+	t.Run("valid values pass", func(t *testing.T) {
+		l := New().WithSource(MapSource{"AGE": "30", "ROLE": "admin", "CODE": "ABC"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("max violation is reported", func(t *testing.T) {
+		l := New().WithSource(MapSource{"AGE": "200", "ROLE": "admin", "CODE": "ABC"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatal("expected a max violation error")
+		}
+	})
+
+	t.Run("oneof violation is reported", func(t *testing.T) {
+		l := New().WithSource(MapSource{"AGE": "30", "ROLE": "root", "CODE": "ABC"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatal("expected an oneof violation error")
+		}
+	})
+
+	t.Run("pattern violation is reported", func(t *testing.T) {
+		l := New().WithSource(MapSource{"AGE": "30", "ROLE": "admin", "CODE": "abc"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatal("expected a pattern violation error")
+		}
+	})
+}
+
+func TestUnmarshal_ConstraintsEnforcedOnSliceElements(t *testing.T) {
+	type Config struct {
+		Users []string `lamenv:"separator=,;min=3"`
+	}
+
+	t.Run("delimited convention", func(t *testing.T) {
+		l := New().WithSource(MapSource{"USERS": "no,robert"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatal("expected a min-length violation on the delimited element \"no\"")
+		}
+	})
+
+	t.Run("indexed convention", func(t *testing.T) {
+		l := New().WithSource(MapSource{"USERS_0": "no", "USERS_1": "robert"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatal("expected a min-length violation on the indexed element \"no\"")
+		}
+	})
+}
+
+func TestUnmarshal_ConstraintsEnforcedOnMapValues(t *testing.T) {
+	type Config struct {
+		Pairs map[string]string `lamenv:"separator=,;keyval=:;min=3"`
+	}
+
+	t.Run("delimited convention", func(t *testing.T) {
+		l := New().WithSource(MapSource{"PAIRS": "a:no,b:robert"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatal("expected a min-length violation on the delimited entry \"no\"")
+		}
+	})
+
+	t.Run("guessed-prefix convention", func(t *testing.T) {
+		l := New().WithSource(MapSource{"PAIRS_A": "no", "PAIRS_B": "robert"})
+		var c Config
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatal("expected a min-length violation on the guessed-prefix entry \"no\"")
+		}
+	})
+}
+
+type validatedConfig struct {
+	Port int
+}
+
+func (c *validatedConfig) Validate() error {
+	if c.Port < 1024 {
+		return fmt.Errorf("port %d is reserved", c.Port)
+	}
+	return nil
+}
+
+func TestUnmarshal_ValidatorInterface(t *testing.T) {
+	t.Run("rejected by Validate", func(t *testing.T) {
+		l := New().WithSource(MapSource{"PORT": "80"})
+		var c validatedConfig
+		if err := l.Unmarshal(&c, nil); err == nil {
+			t.Fatal("expected Validate to reject the reserved port")
+		}
+	})
+
+	t.Run("accepted by Validate", func(t *testing.T) {
+		l := New().WithSource(MapSource{"PORT": "8080"})
+		var c validatedConfig
+		if err := l.Unmarshal(&c, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+type unregisteredValidated struct {
+	Value string
+}
+
+func TestRegisterValidator_TakesPrecedence(t *testing.T) {
+	l := New().WithSource(MapSource{"VALUE": "reject-me"})
+	l.RegisterValidator(reflect.TypeOf(unregisteredValidated{}), func(v interface{}) error {
+		c := v.(*unregisteredValidated)
+		if c.Value == "reject-me" {
+			return fmt.Errorf("value is not allowed")
+		}
+		return nil
+	})
+	var c unregisteredValidated
+	if err := l.Unmarshal(&c, nil); err == nil {
+		t.Fatal("expected the registered validator to reject the value")
+	}
+}